@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cloud66/starter/common"
+	"github.com/spf13/cobra"
+)
+
+// StatusError reports an unsuccessful command exit, carrying the exit code
+// to use and the message to print. This lets individual commands return a
+// plain error instead of calling os.Exit themselves, following the pattern
+// used by the Docker CLI.
+type StatusError struct {
+	Status     string
+	StatusCode int
+}
+
+func (e StatusError) Error() string {
+	return e.Status
+}
+
+// handleErr converts the error returned by the root command into a process
+// exit code. It is the only place in the binary allowed to call os.Exit.
+func handleErr(err error) {
+	if err == nil {
+		return
+	}
+
+	statusCode := 1
+	if sterr, ok := err.(StatusError); ok && sterr.StatusCode != 0 {
+		statusCode = sterr.StatusCode
+	}
+
+	if msg := err.Error(); msg != "" {
+		common.PrintError(msg)
+	}
+
+	os.Exit(statusCode)
+}
+
+// flagErrorFunc turns a flag parsing error into a StatusError so it goes
+// through the same exit-code handling as every other command failure.
+func flagErrorFunc(cmd *cobra.Command, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return StatusError{
+		Status:     fmt.Sprintf("%s\n\n%s", err.Error(), cmd.UsageString()),
+		StatusCode: 1,
+	}
+}
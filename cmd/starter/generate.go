@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/cloud66/starter/common"
+	"github.com/cloud66/starter/pkg/starter"
+	"github.com/spf13/cobra"
+)
+
+// newGenerateCmd is the current default behavior: detect the project's
+// framework and write out a Dockerfile (and optionally service.yml /
+// docker-compose.yml).
+func newGenerateCmd() *cobra.Command {
+	var (
+		path        string
+		outputPath  string
+		noPrompt    bool
+		environment string
+		overwrite   bool
+		generator   string
+		build       bool
+		buildTags   []string
+		contextTar  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Analyze a project and generate a Dockerfile",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			common.PrintlnTitle("Starter (c) 2016 Cloud66 Inc.")
+
+			result, err := starter.Analyze(starter.Options{
+				Path:            path,
+				OutputPath:      outputPath,
+				Templates:       config.template_path,
+				Branch:          flagBranch,
+				Sources:         config.TemplateSources(flagBranch),
+				UpdateTemplates: true,
+				Environment:     environment,
+				NoPrompt:        noPrompt,
+				Overwrite:       overwrite,
+				Generator:       generator,
+			})
+			if err != nil {
+				return StatusError{Status: err.Error(), StatusCode: 1}
+			}
+
+			if len(result.Warnings) > 0 {
+				common.PrintlnWarning("Warnings:")
+				for _, warning := range result.Warnings {
+					common.PrintlnWarning(" * " + warning)
+				}
+			}
+
+			if result.CommitSHA != "" {
+				common.PrintlnL0("Analyzed commit %s", result.CommitSHA)
+			}
+
+			if build || contextTar != "" {
+				if err := buildOrExportContext(result.OutputPath, build, buildTags, contextTar); err != nil {
+					return StatusError{Status: err.Error(), StatusCode: 1}
+				}
+			}
+
+			common.PrintlnL0("Now you can add the newly created Dockerfile to your git")
+			common.PrintlnL0("To do that you will need to run the following commands:\n\n")
+			fmt.Printf("cd %s\n", result.OutputPath)
+			fmt.Println("git add Dockerfile")
+			fmt.Println("git commit -m 'Adding Dockerfile'")
+			if strings.Contains(generator, "service") {
+				common.PrintlnL0("To create a new Docker Stack with Cloud 66 use the following command:\n\n")
+				fmt.Printf("cx stacks create --name='CHANGEME' --environment='%s' --service_yaml=service.yml\n\n", environment)
+			}
+
+			common.PrintlnTitle("Done")
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&path, "path", "p", "", "project path, or a git URL such as https://github.com/foo/bar@v1.2.3")
+	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "where to write the generated files (defaults to the project path)")
+	cmd.Flags().BoolVarP(&noPrompt, "yes", "y", false, "do not prompt user")
+	cmd.Flags().StringVarP(&environment, "environment", "e", "production", "set project environment")
+	cmd.Flags().BoolVar(&overwrite, "overwrite", false, "overwrite existing files")
+	cmd.Flags().StringVarP(&generator, "generator", "g", "dockerfile", `what kind of files need to be generated by starter:
+	-g dockerfile: only the Dockerfile
+	-g docker-compose: only the docker-compose.yml + Dockerfile
+	-g service: only the service.yml + Dockerfile (cloud 66 specific)
+	-g dockerfile,service,docker-compose (all files)`)
+	cmd.Flags().BoolVar(&build, "build", false, "build the image with the generated Dockerfile via the Docker daemon")
+	cmd.Flags().StringSliceVar(&buildTags, "tag", nil, "image tag(s) to apply when --build is set")
+	cmd.Flags().StringVar(&contextTar, "context-tar", "", "write the build context as a tar file here instead of (or in addition to) building")
+
+	return cmd
+}
+
+// buildOrExportContext packages the generated project as a tar build
+// context and, depending on which flags were set, writes it to disk and/or
+// pipes it straight into the Docker daemon as `docker build` would.
+func buildOrExportContext(dir string, build bool, tags []string, contextTarPath string) error {
+	tarStream, err := starter.BuildContextTar(dir, starter.TarOptions{})
+	if err != nil {
+		return fmt.Errorf("Failed to build the tar context due to %s", err.Error())
+	}
+	defer tarStream.Close()
+
+	var source io.Reader = tarStream
+
+	if contextTarPath != "" {
+		out, err := os.Create(contextTarPath)
+		if err != nil {
+			return fmt.Errorf("Failed to create %s due to %s", contextTarPath, err.Error())
+		}
+		defer out.Close()
+
+		if !build {
+			_, err := io.Copy(out, tarStream)
+			return err
+		}
+
+		source = io.TeeReader(tarStream, out)
+	}
+
+	if !build {
+		return nil
+	}
+
+	common.PrintlnL0("Building image via the Docker daemon")
+	return starter.RunDockerBuild(context.Background(), os.Stdout, source, starter.BuildOptions{Tags: tags})
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"github.com/bugsnag/bugsnag-go"
+)
+
+var (
+	config = &Config{}
+
+	// VERSION holds the starter version
+	VERSION = "dev"
+	// BUILDDATE holds the date starter was built
+	BUILDDATE string
+)
+
+func init() {
+	bugsnag.Configure(bugsnag.Configuration{
+		APIKey:     "916591d12b54e689edde67e641c5843d",
+		AppVersion: VERSION,
+	})
+}
+
+func main() {
+	handleErr(newRootCmd().Execute())
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagTemplates string
+	flagBranch    string
+)
+
+// newRootCmd builds the `starter` command tree.
+func newRootCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "starter",
+		Short:         "Starter analyzes your project and generates a Dockerfile, service.yml and docker-compose.yml",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			config.SetDefaults()
+			// Only override the persisted template source when --templates
+			// was actually passed, otherwise this clobbers whatever `template
+			// use` saved before generate ever gets a chance to read it.
+			if flagTemplates != "" {
+				config.template_path = flagTemplates
+			}
+			return nil
+		},
+	}
+
+	cmd.SetFlagErrorFunc(flagErrorFunc)
+
+	cmd.PersistentFlags().StringVar(&flagTemplates, "templates", "", "location of the templates directory")
+	cmd.PersistentFlags().StringVar(&flagBranch, "branch", "master", "template branch in github")
+
+	cmd.AddCommand(
+		newGenerateCmd(),
+		newVersionCmd(),
+		newTemplateCmd(),
+	)
+
+	return cmd
+}
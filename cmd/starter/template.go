@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/cloud66/starter/common"
+	"github.com/cloud66/starter/pkg/starter"
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+)
+
+// newTemplateCmd groups the template cache management subcommands.
+func newTemplateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "template",
+		Short: "Manage the templates used to generate Dockerfiles, service.yml and docker-compose.yml",
+	}
+
+	cmd.AddCommand(
+		newTemplatePullCmd(),
+		newTemplateListCmd(),
+		newTemplateShowCmd(),
+		newTemplateUseCmd(),
+	)
+
+	return cmd
+}
+
+// newTemplatePullCmd force-refreshes the local template cache, regardless of
+// whether the cached version already matches upstream.
+func newTemplatePullCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "pull",
+		Short: "Force-refresh the local template cache",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := templateCacheDir()
+			if err != nil {
+				return StatusError{Status: err.Error(), StatusCode: 1}
+			}
+
+			common.PrintlnL0("Pulling templates for branch %s into %s", flagBranch, dir)
+
+			sources := config.TemplateSources(flagBranch)
+
+			version, served, err := starter.PullTemplates(dir, sources)
+			if err != nil {
+				return StatusError{Status: err.Error(), StatusCode: 1}
+			}
+
+			common.PrintlnL1("Templates updated to version %s", version)
+			for name, source := range served {
+				common.PrintlnL1(" * %s <- %s", name, source)
+			}
+
+			return nil
+		},
+	}
+}
+
+// newTemplateListCmd prints the version and file names of the currently
+// cached templates.
+func newTemplateListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the cached templates and their version",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := templateCacheDir()
+			if err != nil {
+				return StatusError{Status: err.Error(), StatusCode: 1}
+			}
+
+			version, files, err := starter.ListTemplates(dir)
+			if err != nil {
+				return StatusError{Status: fmt.Sprintf("Failed to read cached templates due to %s", err.Error()), StatusCode: 1}
+			}
+
+			common.PrintlnTitle("Templates version %s", version)
+			for _, f := range files {
+				fmt.Println(" *", f.Name)
+			}
+
+			return nil
+		},
+	}
+}
+
+// newTemplateShowCmd prints the contents of a single cached template file.
+func newTemplateShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <name>",
+		Short: "Print the contents of a cached template file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := templateCacheDir()
+			if err != nil {
+				return StatusError{Status: err.Error(), StatusCode: 1}
+			}
+
+			content, err := ioutil.ReadFile(filepath.Join(dir, args[0]))
+			if err != nil {
+				return StatusError{Status: fmt.Sprintf("Failed to read template %s due to %s", args[0], err.Error()), StatusCode: 1}
+			}
+
+			fmt.Println(string(content))
+
+			return nil
+		},
+	}
+}
+
+// newTemplateUseCmd registers an alternative template source (a local path
+// or a git URL) as the highest-precedence entry in config.TemplateSources,
+// persisted so later runs of generate/pull pick it up without needing
+// -templates on every invocation. A local-dir-only field can't represent a
+// git URL, which is why this goes through the same precedence list the
+// daemon's configured sources use rather than config.template_path.
+func newTemplateUseCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "use <path|git-url>",
+		Short: "Register an alternative template source",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := config.UseTemplateSource(args[0]); err != nil {
+				return StatusError{Status: fmt.Sprintf("Failed to persist template source due to %s", err.Error()), StatusCode: 1}
+			}
+
+			common.PrintlnL0("Using %s as the template source", args[0])
+
+			return nil
+		},
+	}
+}
+
+func templateCacheDir() (string, error) {
+	if flagTemplates != "" {
+		return filepath.Abs(flagTemplates)
+	}
+
+	homeDir, err := homedir.Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(homeDir, ".starter"), nil
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// newVersionCmd prints the version and build date baked in at link time.
+func newVersionCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "version",
+		Short: "Print the Starter version",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fmt.Printf("Starter version: %s (%s)\n", VERSION, BUILDDATE)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"os/signal"
+
+	"github.com/bugsnag/bugsnag-go"
+	"github.com/cloud66/starter/common"
+)
+
+var (
+	config = &Config{}
+
+	// VERSION holds the starter version
+	VERSION = "dev"
+	// BUILDDATE holds the date starter was built
+	BUILDDATE string
+
+	flagConfig string
+)
+
+func init() {
+	bugsnag.Configure(bugsnag.Configuration{
+		APIKey:     "916591d12b54e689edde67e641c5843d",
+		AppVersion: VERSION,
+	})
+
+	flag.StringVar(&flagConfig, "c", "", "configuration path for the daemon")
+}
+
+func main() {
+	flag.Parse()
+
+	if flagConfig != "" {
+		if _, err := os.Stat(flagConfig); os.IsNotExist(err) {
+			common.PrintError("Configuration directory not found: %s", flagConfig)
+			os.Exit(1)
+		}
+
+		common.PrintL0("Using %s for configuration", flagConfig)
+		conf, err := ReadFromFile(flagConfig)
+		if err != nil {
+			common.PrintError("Failed to load configuration file due to %s", err.Error())
+			os.Exit(1)
+		}
+		*config = *conf
+	} else {
+		config.SetDefaults()
+	}
+
+	common.PrintlnTitle("Starter Daemon (c) 2016 Cloud66 Inc.")
+
+	signalChan := make(chan os.Signal, 1)
+	cleanupDone := make(chan bool)
+	signal.Notify(signalChan, os.Interrupt)
+
+	api := NewAPI(config)
+	if err := api.StartAPI(); err != nil {
+		common.PrintError("Unable to start the API due to %s", err.Error())
+		os.Exit(1)
+	}
+
+	go func() {
+		for range signalChan {
+			common.PrintL0("Received an interrupt, stopping services\n")
+			cleanupDone <- true
+		}
+	}()
+
+	<-cleanupDone
+}
@@ -0,0 +1,46 @@
+package starter
+
+import (
+	"context"
+	"io"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
+)
+
+// BuildOptions controls RunDockerBuild.
+type BuildOptions struct {
+	// Tags are the image tags to build, e.g. "myapp:latest".
+	Tags []string
+	// Dockerfile is the path of the Dockerfile within the build context,
+	// relative to its root. Defaults to "Dockerfile".
+	Dockerfile string
+}
+
+// RunDockerBuild pipes tarStream to the Docker daemon as the build context
+// and streams the build output to out, the same way `docker build` itself
+// does. It talks to the daemon directly over the client, so no external
+// `docker` binary is required.
+func RunDockerBuild(ctx context.Context, out io.Writer, tarStream io.Reader, opts BuildOptions) error {
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return err
+	}
+
+	dockerfile := opts.Dockerfile
+	if dockerfile == "" {
+		dockerfile = "Dockerfile"
+	}
+
+	resp, err := cli.ImageBuild(ctx, tarStream, types.ImageBuildOptions{
+		Tags:       opts.Tags,
+		Dockerfile: dockerfile,
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return jsonmessage.DisplayJSONMessagesStream(resp.Body, out, 0, false, nil)
+}
@@ -0,0 +1,152 @@
+package starter
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloud66/starter/common"
+	gogit "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+
+	homedir "github.com/mitchellh/go-homedir"
+)
+
+// isGitSource reports whether path is a git URL rather than a local
+// filesystem path, e.g. "https://github.com/foo/bar@v1.2.3".
+func isGitSource(path string) bool {
+	repo, _ := splitSourceRef(path)
+	u, err := url.Parse(repo)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	return u.Scheme == "http" || u.Scheme == "https" || u.Scheme == "git" || u.Scheme == "ssh"
+}
+
+// splitSourceRef splits "<repo>@<ref>" into its repo and ref. ref is empty
+// when no "@" suffix is present, in which case the default branch is used.
+func splitSourceRef(path string) (repo string, ref string) {
+	idx := strings.LastIndex(path, "@")
+	if idx == -1 || strings.ContainsAny(path[idx+1:], "/:") {
+		return path, ""
+	}
+	return path[:idx], path[idx+1:]
+}
+
+// sourceCacheDir returns the directory a git source is cloned into:
+// ~/.starter/sources/<host>/<path>.
+func sourceCacheDir(repoURL string) (string, error) {
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return "", fmt.Errorf("Failed to parse git URL %s due to %s", repoURL, err.Error())
+	}
+
+	homeDir, err := homedir.Dir()
+	if err != nil {
+		return "", err
+	}
+
+	repoPath := strings.TrimSuffix(strings.TrimPrefix(u.Path, "/"), ".git")
+	return filepath.Join(homeDir, ".starter", "sources", u.Host, repoPath), nil
+}
+
+// resolveSource turns a project path into a local working tree. Local paths
+// are returned unchanged. Git URLs are cloned (or fetched, if already
+// cached) into the source cache, hard-reset onto the requested ref, and the
+// resolved commit SHA is returned alongside the working tree path.
+func resolveSource(path string) (workDir string, commitSHA string, err error) {
+	if !isGitSource(path) {
+		return path, "", nil
+	}
+
+	repoURL, ref := splitSourceRef(path)
+
+	cacheDir, err := sourceCacheDir(repoURL)
+	if err != nil {
+		return "", "", err
+	}
+
+	repo, openErr := gogit.PlainOpen(cacheDir)
+	switch openErr {
+	case gogit.ErrRepositoryNotExists:
+		common.PrintlnL0("Cloning %s into %s", repoURL, cacheDir)
+		repo, err = gogit.PlainClone(cacheDir, false, &gogit.CloneOptions{URL: repoURL})
+		if err != nil {
+			return "", "", fmt.Errorf("Failed to clone %s due to %s", repoURL, err.Error())
+		}
+	case nil:
+		common.PrintlnL0("Refreshing cached clone of %s", repoURL)
+		if err := refreshSource(repo); err != nil {
+			return "", "", err
+		}
+	default:
+		return "", "", fmt.Errorf("Failed to open cached clone of %s due to %s", repoURL, openErr.Error())
+	}
+
+	if err := checkoutRef(repo, ref); err != nil {
+		return "", "", fmt.Errorf("Failed to checkout %s due to %s", ref, err.Error())
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return "", "", err
+	}
+
+	return cacheDir, head.Hash().String(), nil
+}
+
+// refreshSource fetches the latest refs for a cached clone. It only updates
+// refs/remotes/origin/*; advancing the worktree onto whatever was fetched is
+// checkoutRef's job.
+func refreshSource(repo *gogit.Repository) error {
+	remote, err := repo.Remote("origin")
+	if err != nil {
+		return err
+	}
+
+	err = remote.Fetch(&gogit.FetchOptions{Force: true})
+	if err != nil && err != gogit.NoErrAlreadyUpToDate {
+		return err
+	}
+
+	return nil
+}
+
+// checkoutRef hard-resets the worktree onto ref, since a plain git checkout
+// wouldn't move a branch that's already checked out onto the commit a fetch
+// just brought in. ref resolves, in order, as: the branch's remote-tracking
+// ref (refs/remotes/origin/<ref>) — a fresh clone only has a local
+// refs/heads/ ref for the branch it defaulted to, not whichever branch was
+// requested, so this is the one that's actually populated after a fetch — a
+// tag, and finally a raw commit hash. An empty ref means "whatever HEAD
+// already points at", i.e. the branch the clone defaulted to.
+func checkoutRef(repo *gogit.Repository, ref string) error {
+	wt, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+
+	branch := ref
+	if branch == "" {
+		head, err := repo.Head()
+		if err != nil {
+			return err
+		}
+		branch = head.Name().Short()
+	}
+
+	if remoteRef, err := repo.Reference(plumbing.NewRemoteReferenceName("origin", branch), true); err == nil {
+		return wt.Reset(&gogit.ResetOptions{Commit: remoteRef.Hash(), Mode: gogit.HardReset})
+	}
+
+	if ref == "" {
+		return nil
+	}
+
+	if tagRef, err := repo.Reference(plumbing.NewTagReferenceName(ref), true); err == nil {
+		return wt.Reset(&gogit.ResetOptions{Commit: tagRef.Hash(), Mode: gogit.HardReset})
+	}
+
+	return wt.Reset(&gogit.ResetOptions{Commit: plumbing.NewHash(ref), Mode: gogit.HardReset})
+}
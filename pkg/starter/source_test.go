@@ -0,0 +1,160 @@
+package starter
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	homedir "github.com/mitchellh/go-homedir"
+	gogit "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// newBareFixture creates a bare repo at <dir>/upstream.git seeded with one
+// commit on master and a "v1" tag, plus a clone-able file:// remote URL.
+func newBareFixture(t *testing.T, dir string) (repoURL string, firstCommit string) {
+	t.Helper()
+
+	bareDir := filepath.Join(dir, "upstream.git")
+	if _, err := gogit.PlainInit(bareDir, true); err != nil {
+		t.Fatalf("failed to init bare fixture: %s", err)
+	}
+
+	seedDir := filepath.Join(dir, "seed")
+	seedRepo, err := gogit.PlainClone(seedDir, false, &gogit.CloneOptions{URL: bareDir})
+	if err != nil {
+		t.Fatalf("failed to clone seed working copy: %s", err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(seedDir, "README.md"), []byte("hello"), 0644); err != nil {
+		t.Fatalf("failed to write seed file: %s", err)
+	}
+
+	wt, err := seedRepo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get seed worktree: %s", err)
+	}
+	if _, err := wt.Add("README.md"); err != nil {
+		t.Fatalf("failed to stage seed file: %s", err)
+	}
+
+	sig := &object.Signature{Name: "Starter Test", Email: "test@cloud66.com"}
+	hash, err := wt.Commit("initial commit", &gogit.CommitOptions{Author: sig})
+	if err != nil {
+		t.Fatalf("failed to commit seed file: %s", err)
+	}
+
+	if _, err := seedRepo.CreateTag("v1", hash, nil); err != nil {
+		t.Fatalf("failed to tag seed commit: %s", err)
+	}
+
+	if err := seedRepo.Push(&gogit.PushOptions{}); err != nil {
+		t.Fatalf("failed to push seed commit: %s", err)
+	}
+
+	return bareDir, hash.String()
+}
+
+// pushSeedCommit adds a second commit to the seed working copy cloned from
+// bareDir (see newBareFixture) and pushes it, without moving the "v1" tag,
+// so tests can assert a refresh actually advances the branch.
+func pushSeedCommit(t *testing.T, dir string) (commit string) {
+	t.Helper()
+
+	seedDir := filepath.Join(dir, "seed")
+	seedRepo, err := gogit.PlainOpen(seedDir)
+	if err != nil {
+		t.Fatalf("failed to open seed working copy: %s", err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(seedDir, "README.md"), []byte("hello again"), 0644); err != nil {
+		t.Fatalf("failed to update seed file: %s", err)
+	}
+
+	wt, err := seedRepo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get seed worktree: %s", err)
+	}
+	if _, err := wt.Add("README.md"); err != nil {
+		t.Fatalf("failed to stage seed file: %s", err)
+	}
+
+	sig := &object.Signature{Name: "Starter Test", Email: "test@cloud66.com"}
+	hash, err := wt.Commit("second commit", &gogit.CommitOptions{Author: sig})
+	if err != nil {
+		t.Fatalf("failed to commit seed update: %s", err)
+	}
+
+	if err := seedRepo.Push(&gogit.PushOptions{}); err != nil {
+		t.Fatalf("failed to push seed update: %s", err)
+	}
+
+	return hash.String()
+}
+
+func TestResolveSourceClonesAndCheckouts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "starter-source-test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	repoURL, firstCommit := newBareFixture(t, dir)
+
+	// resolveSource resolves the clone cache dir via homedir.Dir(), which
+	// caches its result by default; reset it so our HOME override actually
+	// takes effect instead of silently falling through to the real one.
+	homedir.DisableCache = true
+	home := filepath.Join(dir, "home")
+	t.Setenv("HOME", home)
+
+	workDir, commitSHA, err := resolveSource(repoURL + "@v1")
+	if err != nil {
+		t.Fatalf("resolveSource failed: %s", err)
+	}
+
+	if commitSHA != firstCommit {
+		t.Fatalf("expected commit %s, got %s", firstCommit, commitSHA)
+	}
+
+	if _, err := os.Stat(filepath.Join(workDir, "README.md")); err != nil {
+		t.Fatalf("expected checked out README.md: %s", err)
+	}
+
+	// a second call should refresh (fetch + hard reset) the existing clone
+	// rather than failing because it already exists.
+	workDir2, commitSHA2, err := resolveSource(repoURL + "@v1")
+	if err != nil {
+		t.Fatalf("resolveSource refresh failed: %s", err)
+	}
+	if workDir2 != workDir || commitSHA2 != commitSHA {
+		t.Fatalf("expected refresh to reuse %s at %s, got %s at %s", workDir, commitSHA, workDir2, commitSHA2)
+	}
+
+	// checking out the moving "master" branch (rather than the immutable
+	// "v1" tag) after a new commit has landed upstream should advance the
+	// refreshed clone to that new commit, not stay pinned to firstCommit.
+	secondCommit := pushSeedCommit(t, dir)
+
+	workDir3, commitSHA3, err := resolveSource(repoURL + "@master")
+	if err != nil {
+		t.Fatalf("resolveSource refresh on master failed: %s", err)
+	}
+	if workDir3 != workDir {
+		t.Fatalf("expected refresh to reuse %s, got %s", workDir, workDir3)
+	}
+	if commitSHA3 != secondCommit {
+		t.Fatalf("expected refresh to advance to %s, got %s", secondCommit, commitSHA3)
+	}
+}
+
+func TestResolveSourceLocalPath(t *testing.T) {
+	workDir, commitSHA, err := resolveSource("/tmp/some/local/project")
+	if err != nil {
+		t.Fatalf("resolveSource failed for local path: %s", err)
+	}
+	if workDir != "/tmp/some/local/project" || commitSHA != "" {
+		t.Fatalf("expected local path to pass through unchanged, got %s / %s", workDir, commitSHA)
+	}
+}
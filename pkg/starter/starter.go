@@ -0,0 +1,177 @@
+// Package starter implements the project analyzer behind the starter CLI
+// and daemon: detecting a project's framework and generating a Dockerfile,
+// service.yml and docker-compose.yml for it.
+package starter
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloud66/starter/common"
+	homedir "github.com/mitchellh/go-homedir"
+)
+
+// Options controls a single Analyze call. Every field that used to live in
+// a package-level global is threaded through here instead, so multiple
+// analyses (e.g. concurrent requests in the daemon) don't step on each
+// other's template directories.
+type Options struct {
+	// Path is the project to analyze: a local directory, or a git URL
+	// optionally suffixed with "@ref".
+	Path string
+	// OutputPath is where the generated files are written. Defaults to the
+	// (possibly cloned) project path.
+	OutputPath string
+	// Templates is a local template directory to use instead of the cached
+	// download. Empty means use (and maintain) the default ~/.starter cache.
+	Templates string
+	// Branch is the template branch to fetch when Templates is empty and
+	// Sources isn't set.
+	Branch string
+	// Sources, if set, is the ordered list of template sources to refresh
+	// the ~/.starter cache from, letting callers (e.g. a daemon with several
+	// configured sources) override the plain GitHub-by-branch default.
+	Sources TemplateSources
+	// UpdateTemplates controls whether the ~/.starter cache is refreshed
+	// before use.
+	UpdateTemplates bool
+	Environment     string
+	NoPrompt        bool
+	Overwrite       bool
+	Generator       string
+}
+
+// Result is the outcome of a single Analyze call.
+type Result struct {
+	Warnings         []string
+	OK               bool
+	Language         string
+	Framework        string
+	FrameworkVersion string
+	// CommitSHA is the resolved commit the analysis ran against, set when
+	// Path was a git URL instead of a local directory.
+	CommitSHA string
+	// OutputPath is the directory the generated files were written into.
+	OutputPath string
+}
+
+// Analyze detects the project's framework at opts.Path and writes out a
+// Dockerfile (and, depending on opts.Generator, a service.yml and/or
+// docker-compose.yml) for it.
+func Analyze(opts Options) (*Result, error) {
+	path := opts.Path
+	if path == "" {
+		pwd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("Unable to detect current directory path due to %s", err.Error())
+		}
+		path = pwd
+	}
+
+	workDir, commitSHA, err := resolveSource(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to resolve project source %s due to %s", path, err.Error())
+	}
+
+	out := opts.OutputPath
+	if out == "" {
+		out = workDir
+	}
+
+	result := &Result{OK: false, CommitSHA: commitSHA, OutputPath: out}
+
+	var dockerfileTemplateDir, serviceYAMLTemplateDir, dockerComposeYAMLTemplateDir string
+
+	// if a template directory is specified we're going to use that,
+	// otherwise maintain (and use) the shared download cache
+	if opts.Templates == "" {
+		homeDir, _ := homedir.Dir()
+
+		templates := filepath.Join(homeDir, ".starter")
+		if opts.UpdateTemplates {
+			sources := opts.Sources
+			if sources == nil {
+				sources = TemplateSources{NewGitHubTemplateSource(opts.Branch)}
+			}
+
+			if err := EnsureTemplatesFrom(templates, sources); err != nil {
+				return nil, fmt.Errorf("Failed to download latest templates due to %s", err.Error())
+			}
+		}
+
+		dockerfileTemplateDir = templates
+		serviceYAMLTemplateDir = templates
+		dockerComposeYAMLTemplateDir = templates
+	} else {
+		common.PrintlnTitle("Using local templates at %s", opts.Templates)
+		templates, err := filepath.Abs(opts.Templates)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to use %s for templates due to %s", opts.Templates, err.Error())
+		}
+		dockerfileTemplateDir = templates
+		serviceYAMLTemplateDir = templates
+		dockerComposeYAMLTemplateDir = templates
+	}
+
+	common.PrintlnTitle("Detecting framework for the project at %s", workDir)
+
+	pack, err := Detect(workDir)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to detect framework due to: %s", err.Error())
+	}
+
+	// check for Dockerfile (before analysis to avoid wasting time)
+	dockerfilePath := filepath.Join(out, "Dockerfile")
+	if _, err := os.Stat(dockerfilePath); err == nil {
+		if !opts.Overwrite {
+			return nil, errors.New("Dockerfile already exists. Use overwrite flag to overwrite it")
+		}
+	}
+
+	serviceYAMLPath := filepath.Join(out, "service.yml")
+	if _, err := os.Stat(serviceYAMLPath); err == nil {
+		if !opts.Overwrite {
+			return nil, errors.New("service.yml already exists. Use overwrite flag to overwrite it")
+		}
+	}
+
+	err = pack.Analyze(workDir, opts.Environment, !opts.NoPrompt)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to analyze the project due to: %s", err.Error())
+	}
+
+	err = pack.WriteDockerfile(dockerfileTemplateDir, out, !opts.NoPrompt)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to write Dockerfile due to: %s", err.Error())
+	}
+
+	if strings.Contains(opts.Generator, "service") {
+		err = pack.WriteServiceYAML(serviceYAMLTemplateDir, out, !opts.NoPrompt)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to write service.yml due to: %s", err.Error())
+		}
+	}
+
+	if strings.Contains(opts.Generator, "docker-compose") {
+		err = pack.WriteDockerComposeYAML(dockerComposeYAMLTemplateDir, out, !opts.NoPrompt)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to write docker-compose.yml due to: %s", err.Error())
+		}
+	}
+
+	if len(pack.GetMessages()) > 0 {
+		for _, warning := range pack.GetMessages() {
+			result.Warnings = append(result.Warnings, warning)
+		}
+	}
+
+	result.OK = true
+	result.Language = pack.Name()
+	result.Framework = pack.Framework()
+	result.FrameworkVersion = pack.FrameworkVersion()
+
+	return result, nil
+}
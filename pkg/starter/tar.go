@@ -0,0 +1,66 @@
+package starter
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/pkg/archive"
+)
+
+// TarOptions controls BuildContextTar, mirroring the options the Docker CLI
+// itself uses when it builds a build context.
+type TarOptions struct {
+	// ExcludePatterns are glob patterns (in .dockerignore syntax) excluded
+	// from the tar, on top of whatever a .dockerignore file in dir lists.
+	ExcludePatterns []string
+	// IncludeFiles restricts the tar to walking only these paths (relative
+	// to dir) instead of the whole directory tree. Leave it empty to walk
+	// all of dir, which is what generate wants since the Dockerfile it just
+	// wrote needs its sibling project files alongside it in the context.
+	IncludeFiles []string
+}
+
+// BuildContextTar streams dir as an uncompressed tar, honoring .dockerignore
+// the same way `docker build` does. This mirrors the archive.TarWithOptions
+// pattern used by the Docker CLI to build a context before sending it to the
+// daemon. The Dockerfile itself is always kept even if a .dockerignore
+// pattern would otherwise exclude it.
+func BuildContextTar(dir string, opts TarOptions) (io.ReadCloser, error) {
+	excludes, err := readDockerignore(dir)
+	if err != nil {
+		return nil, err
+	}
+	excludes = append(excludes, opts.ExcludePatterns...)
+	excludes = append(excludes, "!Dockerfile")
+
+	return archive.TarWithOptions(dir, &archive.TarOptions{
+		ExcludePatterns: excludes,
+		IncludeFiles:    opts.IncludeFiles,
+	})
+}
+
+// readDockerignore returns the exclude patterns listed in dir's
+// .dockerignore file, or nil if there isn't one.
+func readDockerignore(dir string) ([]string, error) {
+	raw, err := ioutil.ReadFile(filepath.Join(dir, ".dockerignore"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var patterns []string
+	for _, line := range strings.Split(string(raw), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+
+	return patterns, nil
+}
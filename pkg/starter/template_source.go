@@ -0,0 +1,80 @@
+package starter
+
+import (
+	"fmt"
+	"io"
+)
+
+// TemplateSource provides access to a bundle of Dockerfile/service.yml/
+// docker-compose.yml templates from a single origin. This removes the
+// single-vendor lock-in of fetching templates.json from cloud66/starter on
+// GitHub: a TemplateSource can just as well be a local directory or a
+// private OCI registry.
+type TemplateSource interface {
+	// Name identifies the source for diagnostics and provenance reporting,
+	// e.g. "github:cloud66/starter@master".
+	Name() string
+	// Version reports the version of the template bundle currently
+	// available from this source.
+	Version() (string, error)
+	// List enumerates the files in the template bundle.
+	List() ([]TemplateFile, error)
+	// Open returns a reader for a single template file by name. Callers
+	// must close it.
+	Open(name string) (io.ReadCloser, error)
+}
+
+// TemplateSources is an ordered list of TemplateSource, consulted in turn
+// until one answers. This is how several configured sources (e.g. in the
+// daemon config) express a precedence order.
+type TemplateSources []TemplateSource
+
+// Version returns the version reported by the first source that answers.
+func (s TemplateSources) Version() (string, error) {
+	for _, src := range s {
+		if version, err := src.Version(); err == nil {
+			return version, nil
+		}
+	}
+	return "", fmt.Errorf("no configured template source responded")
+}
+
+// List merges the file listing from every source that answers, letting
+// earlier sources shadow files of the same name from later ones.
+func (s TemplateSources) List() ([]TemplateFile, error) {
+	seen := map[string]bool{}
+	var files []TemplateFile
+
+	for _, src := range s {
+		list, err := src.List()
+		if err != nil {
+			continue
+		}
+
+		for _, f := range list {
+			if seen[f.Name] {
+				continue
+			}
+			seen[f.Name] = true
+			files = append(files, f)
+		}
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no configured template source responded")
+	}
+
+	return files, nil
+}
+
+// Open returns the first source's copy of name, along with the source that
+// served it, so callers like `template pull` can report provenance.
+func (s TemplateSources) Open(name string) (io.ReadCloser, TemplateSource, error) {
+	for _, src := range s {
+		r, err := src.Open(name)
+		if err == nil {
+			return r, src, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("template %s not found in any configured source", name)
+}
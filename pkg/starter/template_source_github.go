@@ -0,0 +1,74 @@
+package starter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const templatePath = "https://raw.githubusercontent.com/cloud66/starter/{{.branch}}/templates/templates.json"
+
+// githubTemplateSource fetches templates.json (and the files it lists) from
+// the cloud66/starter GitHub repo, the original and still the default
+// template source.
+type githubTemplateSource struct {
+	branch string
+	tv     *templateDefinition
+}
+
+// NewGitHubTemplateSource serves templates from the cloud66/starter GitHub
+// repo at branch.
+func NewGitHubTemplateSource(branch string) TemplateSource {
+	return &githubTemplateSource{branch: branch}
+}
+
+func (s *githubTemplateSource) Name() string {
+	return fmt.Sprintf("github:cloud66/starter@%s", s.branch)
+}
+
+func (s *githubTemplateSource) Version() (string, error) {
+	tv, err := s.definition()
+	if err != nil {
+		return "", err
+	}
+	return tv.Version, nil
+}
+
+func (s *githubTemplateSource) List() ([]TemplateFile, error) {
+	tv, err := s.definition()
+	if err != nil {
+		return nil, err
+	}
+
+	return categorizedTemplateFiles(tv), nil
+}
+
+func (s *githubTemplateSource) Open(name string) (io.ReadCloser, error) {
+	tv, err := s.definition()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range allTemplateFiles(tv) {
+		if f.Name == name {
+			return fetch(strings.Replace(f.URL, "{{.branch}}", s.branch, -1), nil)
+		}
+	}
+
+	return nil, fmt.Errorf("template %s not found in %s", name, s.Name())
+}
+
+func (s *githubTemplateSource) definition() (templateDefinition, error) {
+	if s.tv != nil {
+		return *s.tv, nil
+	}
+
+	var tv templateDefinition
+	err := fetchJSON(strings.Replace(templatePath, "{{.branch}}", s.branch, -1), nil, &tv)
+	if err != nil {
+		return tv, err
+	}
+
+	s.tv = &tv
+	return tv, nil
+}
@@ -0,0 +1,69 @@
+package starter
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// localTemplateSource serves templates out of a local directory, using the
+// same templates.json layout as the GitHub source so existing `-templates`
+// checkouts keep working unchanged. dir may also be a git URL (optionally
+// "@ref"-suffixed): it's resolved through the same resolveSource used for
+// project sources, so a plain local directory is returned unchanged while a
+// git URL is cloned/refreshed into the source cache first.
+type localTemplateSource struct {
+	dir string
+}
+
+// NewLocalTemplateSource serves templates from a local directory or a git
+// URL pointing at one.
+func NewLocalTemplateSource(dir string) TemplateSource {
+	return &localTemplateSource{dir: dir}
+}
+
+func (s *localTemplateSource) Name() string {
+	return fmt.Sprintf("local:%s", s.dir)
+}
+
+func (s *localTemplateSource) Version() (string, error) {
+	dir, err := s.resolvedDir()
+	if err != nil {
+		return "", err
+	}
+
+	tv, err := readLocalTemplateDefinition(dir)
+	if err != nil {
+		return "", err
+	}
+	return tv.Version, nil
+}
+
+func (s *localTemplateSource) List() ([]TemplateFile, error) {
+	dir, err := s.resolvedDir()
+	if err != nil {
+		return nil, err
+	}
+
+	tv, err := readLocalTemplateDefinition(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return categorizedTemplateFiles(tv), nil
+}
+
+func (s *localTemplateSource) Open(name string) (io.ReadCloser, error) {
+	dir, err := s.resolvedDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(filepath.Join(dir, name))
+}
+
+func (s *localTemplateSource) resolvedDir() (string, error) {
+	dir, _, err := resolveSource(s.dir)
+	return dir, err
+}
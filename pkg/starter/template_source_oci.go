@@ -0,0 +1,124 @@
+package starter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/memory"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+// ociTemplateSource pulls a template bundle from an OCI image reference
+// (e.g. "registry.example.com/templates/starter:v3") using ORAS, so
+// operators can host curated, signed templates alongside their images in a
+// private registry instead of relying on a single public GitHub repo.
+//
+// Each file in the bundle is expected to be its own layer, named via the
+// standard org.opencontainers.image.title annotation.
+type ociTemplateSource struct {
+	ref string
+
+	digest string
+	files  map[string][]byte
+}
+
+// NewOCITemplateSource serves templates from the OCI artifact at ref.
+func NewOCITemplateSource(ref string) TemplateSource {
+	return &ociTemplateSource{ref: ref}
+}
+
+func (s *ociTemplateSource) Name() string {
+	return fmt.Sprintf("oci:%s", s.ref)
+}
+
+func (s *ociTemplateSource) Version() (string, error) {
+	if err := s.pull(); err != nil {
+		return "", err
+	}
+	return s.digest, nil
+}
+
+func (s *ociTemplateSource) List() ([]TemplateFile, error) {
+	if err := s.pull(); err != nil {
+		return nil, err
+	}
+
+	files := make([]TemplateFile, 0, len(s.files))
+	for name := range s.files {
+		files = append(files, TemplateFile{Name: name})
+	}
+	return files, nil
+}
+
+func (s *ociTemplateSource) Open(name string) (io.ReadCloser, error) {
+	if err := s.pull(); err != nil {
+		return nil, err
+	}
+
+	data, ok := s.files[name]
+	if !ok {
+		return nil, fmt.Errorf("template %s not found in %s", name, s.Name())
+	}
+
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
+
+// pull fetches the manifest and every named layer from the registry into
+// memory. The result is cached on the source so List/Open/Version only hit
+// the registry once per process.
+func (s *ociTemplateSource) pull() error {
+	if s.files != nil {
+		return nil
+	}
+
+	ctx := context.Background()
+
+	repo, err := remote.NewRepository(s.ref)
+	if err != nil {
+		return fmt.Errorf("Failed to resolve OCI repository %s due to %s", s.ref, err.Error())
+	}
+
+	dst := memory.New()
+
+	desc, err := oras.Copy(ctx, repo, repo.Reference.Reference, dst, repo.Reference.Reference, oras.DefaultCopyOptions)
+	if err != nil {
+		return fmt.Errorf("Failed to pull template bundle %s due to %s", s.ref, err.Error())
+	}
+
+	manifestBytes, err := content.FetchAll(ctx, dst, desc)
+	if err != nil {
+		return fmt.Errorf("Failed to read manifest for %s due to %s", s.ref, err.Error())
+	}
+
+	var manifest v1.Manifest
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return fmt.Errorf("Failed to parse manifest for %s due to %s", s.ref, err.Error())
+	}
+
+	files := make(map[string][]byte, len(manifest.Layers))
+	for _, layer := range manifest.Layers {
+		name := layer.Annotations[v1.AnnotationTitle]
+		if name == "" {
+			continue
+		}
+
+		data, err := content.FetchAll(ctx, dst, layer)
+		if err != nil {
+			return fmt.Errorf("Failed to read %s from %s due to %s", name, s.ref, err.Error())
+		}
+
+		files[name] = data
+	}
+
+	s.digest = desc.Digest.String()
+	s.files = files
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package starter
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cloud66/starter/common"
+)
+
+type downloadFile struct {
+	URL  string `json:"url"`
+	Name string `json:"name"`
+}
+
+type templateDefinition struct {
+	Version           string         `json:"version"`
+	Dockerfiles       []downloadFile `json:"dockerfiles"`
+	ServiceYmls       []downloadFile `json:"service-ymls"`
+	DockerComposeYmls []downloadFile `json:"docker-compose-ymls"`
+}
+
+// TemplateFile describes one template file within a bundle.
+type TemplateFile struct {
+	Name string
+	// Category is one of "dockerfiles", "service-ymls" or
+	// "docker-compose-ymls" — the templates.json bucket this file belongs
+	// to. Sources that don't have a notion of category (e.g. an OCI bundle)
+	// leave it empty, which PullTemplates treats as "dockerfiles".
+	Category string
+}
+
+// EnsureTemplates downloads the latest templates for branch into dir, if the
+// local copy is missing or out of date.
+func EnsureTemplates(dir, branch string) error {
+	return EnsureTemplatesFrom(dir, TemplateSources{NewGitHubTemplateSource(branch)})
+}
+
+// EnsureTemplatesFrom is like EnsureTemplates, but consults sources in
+// precedence order instead of always going to GitHub. This is how a daemon
+// (or CLI) with several configured template sources keeps its cache fresh.
+func EnsureTemplatesFrom(dir string, sources TemplateSources) error {
+	version, err := sources.Version()
+	if err != nil {
+		return err
+	}
+
+	localTv, localErr := readLocalTemplateDefinition(dir)
+	if localErr == nil && localTv.Version == version {
+		common.PrintlnL1("Local templates are up to date")
+		return nil
+	}
+
+	if os.IsNotExist(localErr) {
+		common.PrintlnL1("No local templates found. Downloading now.")
+	} else if localErr == nil {
+		common.PrintlnL2("Newer templates found. Downloading them now")
+	}
+
+	_, _, err = PullTemplates(dir, sources)
+	return err
+}
+
+// PullTemplates force-downloads the template bundle into dir from sources,
+// trying each in the given precedence order, regardless of whether the
+// local cache is already up to date. It returns the served version and,
+// for every file, the name of the source that served it. The manifest
+// (templates.json) is reconstructed from what was actually served and
+// written to dir alongside the files, so ListTemplates/EnsureTemplates keep
+// working against the result.
+func PullTemplates(dir string, sources TemplateSources) (version string, served map[string]string, err error) {
+	version, err = sources.Version()
+	if err != nil {
+		return "", nil, err
+	}
+
+	files, err := sources.List()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return "", nil, err
+	}
+
+	served = make(map[string]string, len(files))
+	tv := templateDefinition{Version: version}
+
+	for _, f := range files {
+		r, src, err := sources.Open(f.Name)
+		if err != nil {
+			return "", nil, err
+		}
+
+		err = writeTemplateFile(dir, f.Name, r)
+		r.Close()
+		if err != nil {
+			return "", nil, err
+		}
+
+		served[f.Name] = src.Name()
+
+		df := downloadFile{Name: f.Name}
+		switch f.Category {
+		case "service-ymls":
+			tv.ServiceYmls = append(tv.ServiceYmls, df)
+		case "docker-compose-ymls":
+			tv.DockerComposeYmls = append(tv.DockerComposeYmls, df)
+		default:
+			tv.Dockerfiles = append(tv.Dockerfiles, df)
+		}
+	}
+
+	if err := writeLocalTemplateDefinition(dir, tv); err != nil {
+		return "", nil, err
+	}
+
+	return version, served, nil
+}
+
+// ListTemplates returns the cached bundle's version and file names for dir.
+func ListTemplates(dir string) (version string, files []TemplateFile, err error) {
+	tv, err := readLocalTemplateDefinition(dir)
+	if err != nil {
+		return "", nil, err
+	}
+
+	for _, f := range allTemplateFiles(tv) {
+		files = append(files, TemplateFile{Name: f.Name})
+	}
+
+	return tv.Version, files, nil
+}
+
+func writeTemplateFile(dir, name string, r io.Reader) error {
+	output, err := os.Create(filepath.Join(dir, name))
+	if err != nil {
+		return err
+	}
+	defer output.Close()
+
+	_, err = io.Copy(output, r)
+	return err
+}
+
+func readLocalTemplateDefinition(dir string) (templateDefinition, error) {
+	var tv templateDefinition
+
+	raw, err := ioutil.ReadFile(filepath.Join(dir, "templates.json"))
+	if err != nil {
+		return tv, err
+	}
+
+	err = json.Unmarshal(raw, &tv)
+	return tv, err
+}
+
+func writeLocalTemplateDefinition(dir string, tv templateDefinition) error {
+	raw, err := json.Marshal(tv)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(dir, "templates.json"), raw, 0644)
+}
+
+func allTemplateFiles(tv templateDefinition) []downloadFile {
+	files := make([]downloadFile, 0, len(tv.Dockerfiles)+len(tv.ServiceYmls)+len(tv.DockerComposeYmls))
+	files = append(files, tv.Dockerfiles...)
+	files = append(files, tv.ServiceYmls...)
+	files = append(files, tv.DockerComposeYmls...)
+	return files
+}
+
+// categorizedTemplateFiles is allTemplateFiles but keeps each file's
+// templates.json bucket, for sources that need to reconstruct a manifest
+// (PullTemplates) rather than just listing names (ListTemplates).
+func categorizedTemplateFiles(tv templateDefinition) []TemplateFile {
+	files := make([]TemplateFile, 0, len(tv.Dockerfiles)+len(tv.ServiceYmls)+len(tv.DockerComposeYmls))
+	for _, f := range tv.Dockerfiles {
+		files = append(files, TemplateFile{Name: f.Name, Category: "dockerfiles"})
+	}
+	for _, f := range tv.ServiceYmls {
+		files = append(files, TemplateFile{Name: f.Name, Category: "service-ymls"})
+	}
+	for _, f := range tv.DockerComposeYmls {
+		files = append(files, TemplateFile{Name: f.Name, Category: "docker-compose-ymls"})
+	}
+	return files
+}